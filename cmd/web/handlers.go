@@ -0,0 +1,158 @@
+package main
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"strconv"
+
+	"Templay/pkg/cadence"
+)
+
+// handleEstimate serves GET /estimate?height=1.75&speed=2.68, returning the
+// cadence estimate as JSON.
+func (s *server) handleEstimate(w http.ResponseWriter, r *http.Request) {
+	height, err := parseFloatParam(r, "height")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	speed, err := parseFloatParam(r, "speed")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if height <= 0 {
+		http.Error(w, "height parameter must be positive", http.StatusBadRequest)
+		return
+	}
+
+	bpm, strideLength := cadence.BPMEstimateSimple(height, speed)
+	writeJSON(w, http.StatusOK, map[string]float64{
+		"bpm":          bpm,
+		"strideLength": strideLength,
+	})
+}
+
+// handleSearch serves GET /search?q=...&limit=5, proxying to Spotify search
+// using the server's cached client-credentials token.
+func (s *server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query().Get("q")
+	if q == "" {
+		http.Error(w, "missing q parameter", http.StatusBadRequest)
+		return
+	}
+
+	limit := 5
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			http.Error(w, "invalid limit parameter", http.StatusBadRequest)
+			return
+		}
+		limit = parsed
+	}
+
+	tracks, err := s.spotifyClient.SearchTracks(r.Context(), q, limit)
+	if err != nil {
+		s.logger.Error("spotify search failed", "query", q, "error", err)
+		http.Error(w, "search failed", http.StatusBadGateway)
+		return
+	}
+	writeJSON(w, http.StatusOK, tracks)
+}
+
+// handleMatch serves GET /match?artist=...&title=..., walking the
+// -providers resolver chain (Spotify, then Bandcamp/MusicBrainz fallbacks)
+// for a track Spotify search alone doesn't carry.
+func (s *server) handleMatch(w http.ResponseWriter, r *http.Request) {
+	artist := r.URL.Query().Get("artist")
+	title := r.URL.Query().Get("title")
+	if artist == "" || title == "" {
+		http.Error(w, "artist and title parameters are required", http.StatusBadRequest)
+		return
+	}
+
+	match, err := s.matchChain.Resolve(r.Context(), artist, title)
+	if err != nil {
+		s.logger.Error("musicmatch resolve failed", "artist", artist, "title", title, "error", err)
+		http.Error(w, "match failed", http.StatusBadGateway)
+		return
+	}
+	if match == nil {
+		http.Error(w, "no match found via any provider", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, match)
+}
+
+// handleIndex serves the landing page, and, once height/speed/artist are
+// all present on the query string, builds and renders a cadence playlist.
+func (s *server) handleIndex(w http.ResponseWriter, r *http.Request) {
+	client := s.spotifyClient
+	if id, err := sessionID(w, r); err != nil {
+		s.logger.Error("assign session", "error", err)
+	} else if userClient, ok := s.clientForSession(id); ok {
+		client = userClient
+	}
+
+	q := r.URL.Query()
+	data := indexData{Artist: q.Get("artist")}
+	if raw := q.Get("height"); raw != "" {
+		data.Height, _ = strconv.ParseFloat(raw, 64)
+	}
+	if raw := q.Get("speed"); raw != "" {
+		data.Speed, _ = strconv.ParseFloat(raw, 64)
+	}
+
+	if data.Height > 0 && data.Speed > 0 && data.Artist != "" {
+		bpm, _ := cadence.BPMEstimateSimple(data.Height, data.Speed)
+		data.BPM = bpm
+
+		tracks, err := cadence.BuildCadencePlaylist(r.Context(), client, data.Height, data.Speed, cadence.PlaylistOpts{
+			Artists: []string{data.Artist},
+		})
+		if err != nil {
+			s.logger.Error("build cadence playlist", "artist", data.Artist, "error", err)
+			data.Error = "couldn't build a playlist right now"
+		}
+		for _, t := range tracks {
+			data.Tracks = append(data.Tracks, cadenceTrackView{Name: t.Name, TempoBPM: t.TempoBPM})
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := indexTemplate.Execute(w, data); err != nil {
+		s.logger.Error("render index template", "error", err)
+	}
+}
+
+func parseFloatParam(r *http.Request, name string) (float64, error) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return 0, paramError(name, "missing")
+	}
+	v, err := strconv.ParseFloat(raw, 64)
+	if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, paramError(name, "must be a finite number")
+	}
+	return v, nil
+}
+
+func paramError(name, reason string) error {
+	return &paramErr{name: name, reason: reason}
+}
+
+type paramErr struct {
+	name, reason string
+}
+
+func (e *paramErr) Error() string {
+	return e.name + " parameter " + e.reason
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"net/http"
+	"path/filepath"
+
+	"Templay/pkg/spotify"
+)
+
+// userScopes are the Spotify scopes Templay needs to build and save cadence
+// playlists to a user's account.
+var userScopes = []string{"playlist-modify-public", "playlist-modify-private"}
+
+func (s *server) authCodeFlow() *spotify.AuthCodeFlow {
+	return &spotify.AuthCodeFlow{
+		ClientID:    s.clientID,
+		RedirectURL: s.baseURL + "/callback",
+		Scopes:      userScopes,
+	}
+}
+
+// handleLogin starts the Authorization Code + PKCE flow for the caller's
+// session, redirecting to Spotify's consent screen. /callback completes it.
+func (s *server) handleLogin(w http.ResponseWriter, r *http.Request) {
+	id, err := sessionID(w, r)
+	if err != nil {
+		s.logger.Error("assign session", "error", err)
+		http.Error(w, "session error", http.StatusInternalServerError)
+		return
+	}
+
+	verifier, err := spotify.NewPKCEVerifier()
+	if err != nil {
+		s.logger.Error("generate pkce verifier", "error", err)
+		http.Error(w, "login error", http.StatusInternalServerError)
+		return
+	}
+	state, err := spotify.NewPKCEVerifier()
+	if err != nil {
+		s.logger.Error("generate pkce state", "error", err)
+		http.Error(w, "login error", http.StatusInternalServerError)
+		return
+	}
+	s.sessions.setPending(id, pendingAuth{state: state, verifier: verifier})
+
+	http.Redirect(w, r, s.authCodeFlow().AuthorizeURL(state, verifier), http.StatusFound)
+}
+
+// handleCallback completes the flow started by handleLogin: it validates
+// the state nonce, exchanges the code for a user token, and installs a
+// user-scoped client for this session.
+func (s *server) handleCallback(w http.ResponseWriter, r *http.Request) {
+	id, err := sessionID(w, r)
+	if err != nil {
+		s.logger.Error("assign session", "error", err)
+		http.Error(w, "session error", http.StatusInternalServerError)
+		return
+	}
+
+	pending, ok := s.sessions.takePending(id)
+	if !ok {
+		http.Error(w, "no login in progress for this session", http.StatusBadRequest)
+		return
+	}
+
+	q := r.URL.Query()
+	if errStr := q.Get("error"); errStr != "" {
+		http.Error(w, "authorization denied: "+errStr, http.StatusBadRequest)
+		return
+	}
+	if q.Get("state") != pending.state {
+		http.Error(w, "state mismatch", http.StatusBadRequest)
+		return
+	}
+
+	tok, err := s.authCodeFlow().ExchangeCode(r.Context(), q.Get("code"), pending.verifier)
+	if err != nil {
+		s.logger.Error("exchange spotify auth code", "error", err)
+		http.Error(w, "token exchange failed", http.StatusBadGateway)
+		return
+	}
+
+	store := s.tokenStoreFor(id)
+	if store != nil {
+		if err := store.Save(tok); err != nil {
+			s.logger.Error("persist spotify token", "session", id, "error", err)
+		}
+	}
+
+	userClient := spotify.NewClient(s.clientID, s.clientSecret).WithUserToken(tok).WithTokenStore(store)
+	cancel := userClient.StartUserTokenRefresh(s.refreshCtx)
+	s.sessions.set(id, userClient, cancel)
+
+	http.Redirect(w, r, "/", http.StatusFound)
+}
+
+// tokenStoreFor builds the per-session token store used to persist id's user
+// token, or nil if -token-store-dir was not configured.
+func (s *server) tokenStoreFor(id string) spotify.TokenStore {
+	if s.tokenStoreDir == "" {
+		return nil
+	}
+	return spotify.NewFileTokenStore(filepath.Join(s.tokenStoreDir, id+".token"), s.tokenKey)
+}
+
+// clientForSession returns the user-scoped Spotify client for a session,
+// restoring it from the configured token store if the process has restarted
+// since the session last logged in (the session cookie in the browser can
+// easily outlive the server process).
+func (s *server) clientForSession(id string) (*spotify.Client, bool) {
+	if c, ok := s.sessions.clientFor(id); ok {
+		return c, true
+	}
+	if s.tokenStoreDir == "" {
+		return nil, false
+	}
+
+	store := s.tokenStoreFor(id)
+	tok, err := store.Load()
+	if err != nil {
+		s.logger.Error("load stored spotify token", "session", id, "error", err)
+		return nil, false
+	}
+	if tok == nil {
+		return nil, false
+	}
+
+	userClient := spotify.NewClient(s.clientID, s.clientSecret).WithUserToken(tok).WithTokenStore(store)
+	cancel := userClient.StartUserTokenRefresh(s.refreshCtx)
+	s.sessions.set(id, userClient, cancel)
+	return userClient, true
+}
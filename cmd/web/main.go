@@ -1,167 +1,76 @@
 package main
 
 import (
-	"bytes"
 	"context"
-	"encoding/base64"
-	"encoding/json"
-	"fmt"
-	"net/http"
-	"net/url"
+	"encoding/hex"
+	"flag"
+	"log/slog"
 	"os"
-	"time"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"Templay/pkg/musicmatch"
+	"Templay/pkg/spotify"
 )
 
 func main() {
-	client_id := os.Getenv("SPOTIFY_CLIENT_ID")
-	client_secret := os.Getenv("SPOTIFY_CLIENT_SECRET")
-
-	if client_id == "" || client_secret == "" {
-		fmt.Println("Missing SPOTIFY_CLIENT_ID or SPOTIFY_CLIENT_SECRET in env")
-		return
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	token, expiresIn, err := getAccessToken(ctx, client_id, client_secret)
-	if err != nil {
-		fmt.Println("Token fetch failed:", err)
-		return
-	}
-
-	fmt.Printf("token length: %d\n", len(token))
-	fmt.Printf("expires_in (sec): %d\n", expiresIn)
-
-	if err := callSpotifySearchParsed(ctx, token, "Daft Punk", 5); err != nil {
-		fmt.Println("API call failed:", err)
-	}
-
-	bpm, stepLen := BPMEstimateSimple(1.75, 2.68224)
-	fmt.Printf("Estimated cadence: %.0f spm (step length: %.2f m)\n", bpm, stepLen)
-
-}
-
-func getAccessToken(ctx context.Context, clientID, clientSecret string) (string, int, error) {
-	basic := base64.StdEncoding.EncodeToString([]byte(clientID + ":" + clientSecret)) //base64 per Spotify
-
-	form := url.Values{}
-	form.Set("grant_type", "client_credentials")
-
-	//make the POST request
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://accounts.spotify.com/api/token", bytes.NewBufferString(form.Encode()))
-	if err != nil {
-		return "", 0, err
-	}
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.Header.Set("Authorization", "Basic "+basic)
-
-	//send POST request through network
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return "", 0, err
-	}
-	defer resp.Body.Close()
-
-	//print errors if there is one
-	if resp.StatusCode != http.StatusOK {
-		var buf bytes.Buffer
-		_, _ = buf.ReadFrom(resp.Body)
-		return "", 0, fmt.Errorf("status %s: %s", resp.Status, buf.String())
-	}
-
-	var payload struct {
-		AccessToken string `json:"access_token"`
-		ExpiresIn   int    `json:"expires_in"`
-		TokenType   string `json:"token_type"`
-	}
-	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
-		return "", 0, err
-	}
-	return payload.AccessToken, payload.ExpiresIn, nil
-}
-
-// token = Access token, term = seach term, limit = # of results
-func callSpotifySearchParsed(ctx context.Context, token, term string, limit int) error {
-	type artist struct {
-		Name string `json:"name"`
-	}
-	type track struct {
-		Name    string   `json:"name"`
-		Artists []artist `json:"artists"`
-	}
-	type tracksPage struct {
-		Items []track `json:"items"`
-	}
-	type searchResp struct {
-		Tracks tracksPage `json:"tracks"`
-	}
-
-	//build the URL for the endpoint
-	baseURL := "https://api.spotify.com/v1/search"
-	q := url.Values{}
-	q.Set("q", term)
-	q.Set("type", "track")
-	q.Set("limit", fmt.Sprintf("%d", limit))
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"?"+q.Encode(), nil)
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Authorization", "Bearer "+token)
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return err
+	addr := flag.String("addr", ":8080", "address to listen on")
+	baseURL := flag.String("base-url", "http://127.0.0.1:8080", "public base URL, used to build the Spotify OAuth redirect_uri (must match the app's registered redirect URI)")
+	providersFlag := flag.String("providers", "spotify,bandcamp,musicbrainz", "comma-separated resolver chain for /match, in fallback order")
+	tokenStoreDir := flag.String("token-store-dir", "", "directory to persist logged-in sessions' Spotify tokens (encrypted under -token-store-key), so a restart doesn't force re-login; leave empty to keep tokens in memory only")
+	tokenStoreKeyHex := flag.String("token-store-key", "", "hex-encoded 32-byte AES-256 key used to encrypt persisted tokens; required when -token-store-dir is set")
+	flag.Parse()
+
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+	clientID := os.Getenv("SPOTIFY_CLIENT_ID")
+	clientSecret := os.Getenv("SPOTIFY_CLIENT_SECRET")
+	if clientID == "" || clientSecret == "" {
+		logger.Error("missing SPOTIFY_CLIENT_ID or SPOTIFY_CLIENT_SECRET in env")
+		os.Exit(1)
+	}
+
+	var tokenKey [32]byte
+	if *tokenStoreDir != "" {
+		key, err := hex.DecodeString(*tokenStoreKeyHex)
+		if err != nil || len(key) != 32 {
+			logger.Error("-token-store-key must be a hex-encoded 32-byte key when -token-store-dir is set")
+			os.Exit(1)
+		}
+		copy(tokenKey[:], key)
+		if err := os.MkdirAll(*tokenStoreDir, 0o700); err != nil {
+			logger.Error("create token store directory", "error", err)
+			os.Exit(1)
+		}
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode/100 != 2 {
-		var buf bytes.Buffer
-		_, _ = buf.ReadFrom(resp.Body)
-		return fmt.Errorf("search failed: %s: %s", resp.Status, buf.String())
-	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	var out searchResp
-	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
-		return err
-	}
+	client := spotify.NewClient(clientID, clientSecret)
+	chain := buildResolverChain(*providersFlag, client)
+	srv := newServer(client, clientID, clientSecret, *baseURL, chain, logger, *tokenStoreDir, tokenKey)
 
-	if len(out.Tracks.Items) == 0 {
-		fmt.Println("No tracks found.")
-		return nil
+	if err := srv.run(ctx, *addr); err != nil {
+		logger.Error("server stopped with error", "error", err)
+		os.Exit(1)
 	}
-
-	for i, t := range out.Tracks.Items {
-		artist := "Unknown"
-		if len(t.Artists) > 0 {
-			artist = t.Artists[0].Name
-		}
-		fmt.Printf("%2d) %s — %s\n", i+1, artist, t.Name)
-	}
-	return nil
 }
 
-// Function to estimate the BPM or steps per minute.
-func BPMEstimateSimple(height, speed float64) (float64, float64) {
-	L := 0.414 * height //Stride length
-
-	//Account for longer steps when running faster, around ~5mph or ~2.2m/s
-	if speed > 2.2 {
-		scale := 1.0 + 0.25*((speed-2.2)/1.8)
-		if scale > 1.25 {
-			scale = 1.25
+// buildResolverChain turns a comma-separated -providers flag value into a
+// musicmatch.Chain, in the order given. Unknown provider names are ignored.
+func buildResolverChain(providersFlag string, client *spotify.Client) *musicmatch.Chain {
+	var resolvers []musicmatch.Resolver
+	for _, name := range strings.Split(providersFlag, ",") {
+		switch strings.TrimSpace(name) {
+		case "spotify":
+			resolvers = append(resolvers, musicmatch.NewSpotifyResolver(client))
+		case "bandcamp":
+			resolvers = append(resolvers, musicmatch.NewBandcampResolver())
+		case "musicbrainz":
+			resolvers = append(resolvers, musicmatch.NewMusicBrainzResolver("Templay/0.1"))
 		}
-		L = L * scale
 	}
-
-	//cap the stride length to 55% of height
-	maxL := 0.55 * height
-	if L > maxL {
-		L = maxL
-	}
-
-	//calculate BPM
-	bpm := (speed / L) * 60.0
-	return bpm, L
+	return musicmatch.NewChain(resolvers...)
 }
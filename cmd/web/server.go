@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"Templay/pkg/musicmatch"
+	"Templay/pkg/spotify"
+)
+
+// shutdownTimeout bounds how long graceful shutdown waits for in-flight
+// requests to finish before giving up.
+const shutdownTimeout = 10 * time.Second
+
+// server holds the shared dependencies HTTP handlers need.
+type server struct {
+	spotifyClient *spotify.Client
+	clientID      string
+	clientSecret  string
+	baseURL       string // public base URL used to build the /callback redirect_uri
+	matchChain    *musicmatch.Chain
+	sessions      *sessionStore
+	logger        *slog.Logger
+
+	// tokenStoreDir, if non-empty, is a directory where each session's user
+	// token is persisted (encrypted under tokenKey) so a server restart
+	// doesn't force every logged-in browser to re-authorize.
+	tokenStoreDir string
+	tokenKey      [32]byte
+
+	// refreshCtx is used to start each session's background token-refresh
+	// goroutine, so it lives as long as the server rather than the request
+	// that happened to trigger /callback.
+	refreshCtx context.Context
+}
+
+func newServer(client *spotify.Client, clientID, clientSecret, baseURL string, matchChain *musicmatch.Chain, logger *slog.Logger, tokenStoreDir string, tokenKey [32]byte) *server {
+	return &server{
+		spotifyClient: client,
+		clientID:      clientID,
+		clientSecret:  clientSecret,
+		baseURL:       baseURL,
+		matchChain:    matchChain,
+		sessions:      newSessionStore(),
+		logger:        logger,
+		tokenStoreDir: tokenStoreDir,
+		tokenKey:      tokenKey,
+	}
+}
+
+func (s *server) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", s.handleIndex)
+	mux.HandleFunc("/estimate", s.handleEstimate)
+	mux.HandleFunc("/search", s.handleSearch)
+	mux.HandleFunc("/match", s.handleMatch)
+	mux.HandleFunc("/login", s.handleLogin)
+	mux.HandleFunc("/callback", s.handleCallback)
+	return s.withLogging(mux)
+}
+
+// withLogging logs method, path, status, and duration for every request.
+func (s *server) withLogging(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		s.logger.Info("request",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", rec.status,
+			"duration", time.Since(start),
+		)
+	})
+}
+
+// statusRecorder captures the status code written by a handler so the
+// logging middleware can report it.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// run starts the HTTP server on addr and blocks until ctx is canceled, at
+// which point it shuts down gracefully within shutdownTimeout.
+func (s *server) run(ctx context.Context, addr string) error {
+	s.refreshCtx = ctx
+
+	httpServer := &http.Server{
+		Addr:    addr,
+		Handler: s.routes(),
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		s.logger.Info("listening", "addr", addr)
+		if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCh <- err
+			return
+		}
+		errCh <- nil
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		s.logger.Info("shutting down")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := httpServer.Shutdown(shutdownCtx); err != nil {
+			return err
+		}
+		return <-errCh
+	}
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"sync"
+	"time"
+
+	"Templay/pkg/spotify"
+)
+
+const (
+	sessionCookieName = "templay_session"
+	sessionTTL        = 30 * 24 * time.Hour
+)
+
+// pendingAuth is the PKCE state a session is waiting to see echoed back by
+// Spotify's /callback redirect.
+type pendingAuth struct {
+	state    string
+	verifier string
+}
+
+// sessionClient pairs a user-scoped Spotify client with the CancelFunc for
+// the background token-refresh goroutine StartUserTokenRefresh started for
+// it, so a superseded client's goroutine can be stopped instead of leaked.
+type sessionClient struct {
+	client *spotify.Client
+	cancel context.CancelFunc
+}
+
+// sessionStore maps opaque session cookie values to a per-user Spotify
+// client, so each browser session keeps its own OAuth token (from the
+// Authorization Code + PKCE flow) instead of sharing one global login. It
+// also tracks in-flight logins between /login and /callback.
+type sessionStore struct {
+	mu      sync.Mutex
+	clients map[string]sessionClient
+	pending map[string]pendingAuth
+}
+
+func newSessionStore() *sessionStore {
+	return &sessionStore{
+		clients: make(map[string]sessionClient),
+		pending: make(map[string]pendingAuth),
+	}
+}
+
+// clientFor returns the user-scoped client for a session, if one has
+// authorized via Spotify.
+func (s *sessionStore) clientFor(id string) (*spotify.Client, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sc, ok := s.clients[id]
+	return sc.client, ok
+}
+
+// set associates a user-scoped client and its refresh-goroutine CancelFunc
+// with a session, canceling and replacing any previous one (e.g. on
+// re-authorization) so its refresh goroutine doesn't keep running forever.
+func (s *sessionStore) set(id string, c *spotify.Client, cancel context.CancelFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if prev, ok := s.clients[id]; ok && prev.cancel != nil {
+		prev.cancel()
+	}
+	s.clients[id] = sessionClient{client: c, cancel: cancel}
+}
+
+// setPending records the state/verifier for a login started by /login, to
+// be checked and consumed by /callback.
+func (s *sessionStore) setPending(id string, p pendingAuth) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pending[id] = p
+}
+
+// takePending returns and clears the pending login for a session, if any, so
+// a /callback replay can't reuse the same state/verifier twice.
+func (s *sessionStore) takePending(id string) (pendingAuth, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.pending[id]
+	delete(s.pending, id)
+	return p, ok
+}
+
+// sessionID returns the caller's session cookie value, assigning and setting
+// a fresh one if absent or malformed. Session IDs are used to build token
+// store file paths (see tokenStoreFor), so anything not shaped like a value
+// newSessionToken could have produced is rejected rather than trusted.
+func sessionID(w http.ResponseWriter, r *http.Request) (string, error) {
+	if c, err := r.Cookie(sessionCookieName); err == nil && isValidSessionToken(c.Value) {
+		return c.Value, nil
+	}
+
+	id, err := newSessionToken()
+	if err != nil {
+		return "", err
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookieName,
+		Value:    id,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+		Expires:  time.Now().Add(sessionTTL),
+	})
+	return id, nil
+}
+
+// sessionTokenBytes is the amount of random data in a session token; hex
+// encoding doubles it for the string length check in isValidSessionToken.
+const sessionTokenBytes = 16
+
+func newSessionToken() (string, error) {
+	buf := make([]byte, sessionTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// isValidSessionToken reports whether id has the exact shape newSessionToken
+// produces (lowercase hex, fixed length), so a client-supplied cookie can't
+// smuggle path-traversal characters into a token store file path built from
+// the session ID.
+func isValidSessionToken(id string) bool {
+	if len(id) != sessionTokenBytes*2 {
+		return false
+	}
+	for _, r := range id {
+		if !((r >= '0' && r <= '9') || (r >= 'a' && r <= 'f')) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,53 @@
+package main
+
+import "html/template"
+
+// indexTemplate renders the landing page: a small form for height, speed,
+// and a seed artist, plus the resulting cadence playlist once submitted.
+var indexTemplate = template.Must(template.New("index").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+	<meta charset="utf-8">
+	<title>Templay</title>
+</head>
+<body>
+	<h1>Templay</h1>
+	<p><a href="/login">Connect Spotify account</a></p>
+	<form method="get" action="/">
+		<label>Height (m): <input type="number" step="0.01" name="height" value="{{.Height}}"></label><br>
+		<label>Speed (m/s): <input type="number" step="0.01" name="speed" value="{{.Speed}}"></label><br>
+		<label>Seed artist: <input type="text" name="artist" value="{{.Artist}}"></label><br>
+		<button type="submit">Build playlist</button>
+	</form>
+
+	{{if .Error}}
+	<p>Error: {{.Error}}</p>
+	{{end}}
+
+	{{if .Tracks}}
+	<h2>Cadence playlist ({{printf "%.0f" .BPM}} spm)</h2>
+	<ol>
+		{{range .Tracks}}
+		<li>{{.Name}} — {{.TempoBPM}} BPM</li>
+		{{end}}
+	</ol>
+	{{end}}
+</body>
+</html>
+`))
+
+// indexData is the view model for indexTemplate.
+type indexData struct {
+	Height float64
+	Speed  float64
+	Artist string
+	BPM    float64
+	Tracks []cadenceTrackView
+	Error  string
+}
+
+// cadenceTrackView is the subset of cadence.Track the template renders.
+type cadenceTrackView struct {
+	Name     string
+	TempoBPM float64
+}
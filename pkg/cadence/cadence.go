@@ -0,0 +1,203 @@
+// Package cadence builds playlists whose tracks match a runner's step rate,
+// estimated from height and target speed.
+package cadence
+
+import (
+	"context"
+	"math"
+	"sort"
+
+	"Templay/pkg/spotify"
+)
+
+// maxSpotifySeeds is the combined genre+artist+track seed limit Spotify
+// enforces on /recommendations.
+const maxSpotifySeeds = 5
+
+// defaultTolerance is the BPM window used when PlaylistOpts.ToleranceBPM is
+// unset.
+const defaultTolerance = 3.0
+
+// defaultLimit caps playlist size when PlaylistOpts.Limit is unset.
+const defaultLimit = 20
+
+// PlaylistOpts configures BuildCadencePlaylist.
+type PlaylistOpts struct {
+	Genres          []string
+	Artists         []string
+	ToleranceBPM    float64 // default 3 BPM either side of the target
+	AllowHalfDouble bool    // also accept tracks at 0.5x or 2x the target BPM
+	Limit           int     // default 20
+}
+
+// Track is a Spotify track annotated with the audio-feature tempo that
+// qualified it for the playlist.
+type Track struct {
+	spotify.Track
+	TempoBPM float64
+}
+
+// BuildCadencePlaylist estimates a runner's cadence from height and speed,
+// then assembles a playlist of tracks whose tempo matches that cadence
+// within opts.ToleranceBPM (optionally also matching at half/double time).
+func BuildCadencePlaylist(ctx context.Context, client *spotify.Client, height, speed float64, opts PlaylistOpts) ([]Track, error) {
+	targetBPM, _ := BPMEstimateSimple(height, speed)
+
+	tolerance := opts.ToleranceBPM
+	if tolerance <= 0 {
+		tolerance = defaultTolerance
+	}
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+
+	minTempo := targetBPM - tolerance
+	maxTempo := targetBPM + tolerance
+	if opts.AllowHalfDouble {
+		// Widen the server-side filter to cover half/double time too,
+		// otherwise Spotify never returns those candidates for tempoMatches
+		// to recognize below.
+		minTempo = targetBPM/2 - tolerance
+		maxTempo = targetBPM*2 + tolerance
+	}
+	if minTempo < 0 {
+		minTempo = 0
+	}
+
+	seen := make(map[string]bool)
+	var matched []Track
+
+	for _, seeds := range seedBatches(opts.Genres, opts.Artists) {
+		if len(matched) >= limit {
+			break
+		}
+
+		candidates, err := client.Recommendations(ctx, spotify.RecommendationParams{
+			SeedGenres:  seeds.genres,
+			SeedArtists: seeds.artists,
+			TargetTempo: targetBPM,
+			MinTempo:    minTempo,
+			MaxTempo:    maxTempo,
+			Limit:       limit,
+		})
+		if err != nil {
+			return matched, err
+		}
+		if len(candidates) == 0 {
+			continue
+		}
+
+		ids := make([]string, len(candidates))
+		for i, t := range candidates {
+			ids[i] = t.ID
+		}
+		features, err := client.GetAudioFeatures(ctx, ids)
+		if err != nil {
+			return matched, err
+		}
+		tempoByID := make(map[string]float64, len(features))
+		for _, f := range features {
+			tempoByID[f.ID] = f.Tempo
+		}
+
+		for _, t := range candidates {
+			if seen[t.ID] {
+				continue
+			}
+			tempo, ok := tempoByID[t.ID]
+			if !ok || !tempoMatches(tempo, targetBPM, tolerance, opts.AllowHalfDouble) {
+				continue
+			}
+			seen[t.ID] = true
+			matched = append(matched, Track{Track: t, TempoBPM: tempo})
+			if len(matched) >= limit {
+				break
+			}
+		}
+	}
+
+	sort.Slice(matched, func(i, j int) bool {
+		return math.Abs(matched[i].TempoBPM-targetBPM) < math.Abs(matched[j].TempoBPM-targetBPM)
+	})
+	return matched, nil
+}
+
+// tempoMatches reports whether tempo falls within tolerance of target, or
+// (when allowHalfDouble is set) within tolerance of half or double the
+// target — runners commonly stride on every other beat.
+func tempoMatches(tempo, target, tolerance float64, allowHalfDouble bool) bool {
+	if math.Abs(tempo-target) <= tolerance {
+		return true
+	}
+	if allowHalfDouble {
+		if math.Abs(tempo-target/2) <= tolerance {
+			return true
+		}
+		if math.Abs(tempo-target*2) <= tolerance {
+			return true
+		}
+	}
+	return false
+}
+
+type seedBatch struct {
+	genres  []string
+	artists []string
+}
+
+// seedBatches splits genres/artists into batches that respect Spotify's
+// combined 5-seed limit per /recommendations call, so a long seed list still
+// gets used across several requests instead of being silently truncated.
+func seedBatches(genres, artists []string) []seedBatch {
+	if len(genres) == 0 && len(artists) == 0 {
+		return nil
+	}
+
+	var batches []seedBatch
+	for len(genres) > 0 || len(artists) > 0 {
+		var batch seedBatch
+		budget := maxSpotifySeeds
+
+		take := budget
+		if take > len(genres) {
+			take = len(genres)
+		}
+		batch.genres, genres = genres[:take], genres[take:]
+		budget -= take
+
+		take = budget
+		if take > len(artists) {
+			take = len(artists)
+		}
+		batch.artists, artists = artists[:take], artists[take:]
+
+		batches = append(batches, batch)
+	}
+	return batches
+}
+
+// BPMEstimateSimple estimates a runner's steps-per-minute cadence and stride
+// length from height and speed (both in metric units: meters, meters/sec).
+func BPMEstimateSimple(height, speed float64) (float64, float64) {
+	L := 0.414 * height //Stride length
+
+	//Account for longer steps when running faster, around ~5mph or ~2.2m/s
+	if speed > 2.2 {
+		scale := 1.0 + 0.25*((speed-2.2)/1.8)
+		if scale > 1.25 {
+			scale = 1.25
+		}
+		L = L * scale
+	}
+
+	//cap the stride length to 55% of height
+	maxL := 0.55 * height
+	if L > maxL {
+		L = maxL
+	}
+
+	//calculate BPM
+	bpm := (speed / L) * 60.0
+	return bpm, L
+}
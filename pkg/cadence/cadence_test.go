@@ -0,0 +1,65 @@
+package cadence
+
+import "testing"
+
+func TestTempoMatches(t *testing.T) {
+	const target = 160.0
+	const tolerance = 3.0
+
+	cases := []struct {
+		name            string
+		tempo           float64
+		allowHalfDouble bool
+		want            bool
+	}{
+		{"within tolerance", 161, false, true},
+		{"outside tolerance", 150, false, false},
+		{"half time disallowed", 80, false, false},
+		{"half time allowed", 80, true, true},
+		{"double time allowed", 320, true, true},
+		{"double time disallowed", 320, false, false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := tempoMatches(c.tempo, target, tolerance, c.allowHalfDouble)
+			if got != c.want {
+				t.Errorf("tempoMatches(%v, %v, %v, %v) = %v, want %v", c.tempo, target, tolerance, c.allowHalfDouble, got, c.want)
+			}
+		})
+	}
+}
+
+func TestSeedBatchesRespectsSpotifySeedLimit(t *testing.T) {
+	genres := []string{"rock", "pop", "house", "techno", "jazz", "funk"}
+	artists := []string{"a", "b", "c"}
+
+	batches := seedBatches(genres, artists)
+	if len(batches) == 0 {
+		t.Fatal("expected at least one batch")
+	}
+	for _, b := range batches {
+		if total := len(b.genres) + len(b.artists); total > maxSpotifySeeds {
+			t.Errorf("batch %+v exceeds maxSpotifySeeds: %d", b, total)
+		}
+	}
+
+	var gotGenres, gotArtists int
+	for _, b := range batches {
+		gotGenres += len(b.genres)
+		gotArtists += len(b.artists)
+	}
+	if gotGenres != len(genres) || gotArtists != len(artists) {
+		t.Errorf("batches dropped seeds: got %d genres, %d artists", gotGenres, gotArtists)
+	}
+}
+
+func TestBPMEstimateSimple(t *testing.T) {
+	bpm, stride := BPMEstimateSimple(1.75, 2.68224)
+	if bpm <= 0 || stride <= 0 {
+		t.Fatalf("expected positive bpm/stride, got bpm=%v stride=%v", bpm, stride)
+	}
+	if stride > 0.55*1.75 {
+		t.Fatalf("stride %v exceeds 55%% of height cap", stride)
+	}
+}
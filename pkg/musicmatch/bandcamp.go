@@ -0,0 +1,80 @@
+package musicmatch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+const bandcampAutocompleteURL = "https://bandcamp.com/api/fuzzysearch/1/autocomplete"
+
+// bandcampConfidence is fixed rather than derived from Bandcamp's search
+// ranking, since the autocomplete endpoint doesn't expose a usable score.
+const bandcampConfidence = 0.7
+
+// httpDoer is satisfied by *http.Client; accepting it lets tests stub
+// transport behavior without a real network call.
+type httpDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// BandcampResolver falls back to Bandcamp's (undocumented) fuzzysearch
+// autocomplete endpoint when a track isn't found elsewhere.
+type BandcampResolver struct {
+	HTTPClient httpDoer
+}
+
+// NewBandcampResolver builds a BandcampResolver using http.DefaultClient.
+func NewBandcampResolver() *BandcampResolver {
+	return &BandcampResolver{HTTPClient: http.DefaultClient}
+}
+
+type bandcampAutocompleteResult struct {
+	Type        string `json:"type"` // "t" = track, "a" = album/artist
+	Name        string `json:"name"`
+	BandName    string `json:"band_name"`
+	ItemURLRoot string `json:"item_url_root"`
+}
+
+func (r *BandcampResolver) Resolve(ctx context.Context, artist, title string) (*MatchResult, error) {
+	q := url.Values{}
+	q.Set("q", fmt.Sprintf("%s %s", artist, title))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, bandcampAutocompleteURL+"?"+q.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("musicmatch: bandcamp autocomplete status %s", resp.Status)
+	}
+
+	var payload struct {
+		Results []bandcampAutocompleteResult `json:"results"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, err
+	}
+
+	for _, res := range payload.Results {
+		if res.Type != "t" {
+			continue
+		}
+		if fuzzyTitleMatch(title, res.Name) && exactArtistMatch(artist, res.BandName) {
+			return &MatchResult{
+				Provider:   "bandcamp",
+				URL:        res.ItemURLRoot,
+				Confidence: bandcampConfidence,
+			}, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,90 @@
+package musicmatch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+const musicbrainzSearchURL = "https://musicbrainz.org/ws/2/recording/"
+
+// MusicBrainzResolver falls back to the MusicBrainz recording search API,
+// which doesn't host audio itself but gives a canonical MusicBrainz page
+// for a recording when neither Spotify nor Bandcamp has a match.
+type MusicBrainzResolver struct {
+	HTTPClient httpDoer
+	// UserAgent identifies the client per MusicBrainz's API etiquette
+	// (https://musicbrainz.org/doc/MusicBrainz_API/Rate_Limiting), e.g.
+	// "Templay/0.1 (contact@example.com)".
+	UserAgent string
+}
+
+// NewMusicBrainzResolver builds a MusicBrainzResolver using http.DefaultClient.
+func NewMusicBrainzResolver(userAgent string) *MusicBrainzResolver {
+	return &MusicBrainzResolver{HTTPClient: http.DefaultClient, UserAgent: userAgent}
+}
+
+type musicbrainzRecording struct {
+	Title        string `json:"title"`
+	Score        int    `json:"score"` // MusicBrainz's own 0-100 confidence
+	ID           string `json:"id"`
+	ArtistCredit []struct {
+		Name string `json:"name"`
+	} `json:"artist-credit"`
+}
+
+func (r *MusicBrainzResolver) Resolve(ctx context.Context, artist, title string) (*MatchResult, error) {
+	q := url.Values{}
+	q.Set("query", fmt.Sprintf("recording:%q AND artist:%q", title, artist))
+	q.Set("fmt", "json")
+	q.Set("limit", "5")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, musicbrainzSearchURL+"?"+q.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if r.UserAgent != "" {
+		req.Header.Set("User-Agent", r.UserAgent)
+	}
+
+	resp, err := r.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("musicmatch: musicbrainz search status %s", resp.Status)
+	}
+
+	var payload struct {
+		Recordings []musicbrainzRecording `json:"recordings"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, err
+	}
+
+	for _, rec := range payload.Recordings {
+		if !fuzzyTitleMatch(title, rec.Title) {
+			continue
+		}
+		matchedArtist := false
+		for _, ac := range rec.ArtistCredit {
+			if exactArtistMatch(artist, ac.Name) {
+				matchedArtist = true
+				break
+			}
+		}
+		if !matchedArtist {
+			continue
+		}
+		return &MatchResult{
+			Provider:   "musicbrainz",
+			URL:        "https://musicbrainz.org/recording/" + rec.ID,
+			Confidence: float64(rec.Score) / 100.0,
+		}, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,57 @@
+// Package musicmatch resolves an (artist, title) pair to a track URL by
+// trying a chain of providers in order, falling back to the next one when
+// the current provider has nothing. A MusicBrainz match is a recording page,
+// which carries no audio. Spotify is normally first in the chain;
+// Bandcamp and MusicBrainz exist for tracks Spotify doesn't carry.
+package musicmatch
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+// MatchResult is a single resolved match from one provider.
+type MatchResult struct {
+	Provider   string
+	URL        string
+	Confidence float64 // 0..1, provider-defined
+}
+
+// Resolver looks up a track by artist and title. It returns (nil, nil) when
+// the provider has no match, reserving the error return for transport/API
+// failures so a chain can distinguish "not found" from "couldn't ask".
+type Resolver interface {
+	Resolve(ctx context.Context, artist, title string) (*MatchResult, error)
+}
+
+// Chain tries each Resolver in order, returning the first match. A
+// resolver's error is treated as "no match from this provider" so one
+// flaky provider doesn't abort the whole chain.
+type Chain struct {
+	resolvers []Resolver
+}
+
+// NewChain builds a Chain that tries resolvers in the given order.
+func NewChain(resolvers ...Resolver) *Chain {
+	return &Chain{resolvers: resolvers}
+}
+
+// Resolve returns the first match found by walking the chain in order, or
+// nil if no provider matched. A resolver's error doesn't abort the chain,
+// but it is logged so an outage (bad API key, network down, a provider
+// rate-limiting) doesn't silently read the same as a legitimate no-match.
+func (c *Chain) Resolve(ctx context.Context, artist, title string) (*MatchResult, error) {
+	for _, r := range c.resolvers {
+		res, err := r.Resolve(ctx, artist, title)
+		if err != nil {
+			slog.Default().Warn("musicmatch: resolver failed, falling back",
+				"resolver", fmt.Sprintf("%T", r), "error", err)
+			continue
+		}
+		if res != nil {
+			return res, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,22 @@
+package musicmatch
+
+import "strings"
+
+// fuzzyTitleMatch reports whether found and query refer to the same title,
+// tolerating extra words on either side (e.g. "Song Name (Remastered)" vs
+// "Song Name"): case-folded, and a match either direction of containment.
+func fuzzyTitleMatch(query, found string) bool {
+	query = strings.ToLower(strings.TrimSpace(query))
+	found = strings.ToLower(strings.TrimSpace(found))
+	if query == "" || found == "" {
+		return false
+	}
+	return strings.Contains(found, query) || strings.Contains(query, found)
+}
+
+// exactArtistMatch reports whether two artist names are the same once
+// case-folded, since unlike titles, fallback providers should not accept a
+// partial artist match.
+func exactArtistMatch(query, found string) bool {
+	return strings.EqualFold(strings.TrimSpace(query), strings.TrimSpace(found))
+}
@@ -0,0 +1,41 @@
+package musicmatch
+
+import "testing"
+
+func TestFuzzyTitleMatch(t *testing.T) {
+	cases := []struct {
+		query, found string
+		want         bool
+	}{
+		{"One More Time", "One More Time", true},
+		{"One More Time", "One More Time (Remastered)", true},
+		{"One More Time (Remastered)", "One More Time", true},
+		{"one more time", "ONE MORE TIME", true},
+		{"One More Time", "Around the World", false},
+		{"", "One More Time", false},
+	}
+
+	for _, c := range cases {
+		if got := fuzzyTitleMatch(c.query, c.found); got != c.want {
+			t.Errorf("fuzzyTitleMatch(%q, %q) = %v, want %v", c.query, c.found, got, c.want)
+		}
+	}
+}
+
+func TestExactArtistMatch(t *testing.T) {
+	cases := []struct {
+		query, found string
+		want         bool
+	}{
+		{"Daft Punk", "Daft Punk", true},
+		{"daft punk", "Daft Punk", true},
+		{" Daft Punk ", "Daft Punk", true},
+		{"Daft Punk", "Daft Punk Tribute", false},
+	}
+
+	for _, c := range cases {
+		if got := exactArtistMatch(c.query, c.found); got != c.want {
+			t.Errorf("exactArtistMatch(%q, %q) = %v, want %v", c.query, c.found, got, c.want)
+		}
+	}
+}
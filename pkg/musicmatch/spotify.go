@@ -0,0 +1,37 @@
+package musicmatch
+
+import (
+	"context"
+	"fmt"
+
+	"Templay/pkg/spotify"
+)
+
+// SpotifyResolver is the primary Resolver, backed by Spotify search. It is
+// treated as authoritative: the first result is returned with full
+// confidence rather than being checked against fuzzyTitleMatch/
+// exactArtistMatch like the fallback providers.
+type SpotifyResolver struct {
+	Client *spotify.Client
+}
+
+// NewSpotifyResolver wraps an existing spotify.Client as a Resolver.
+func NewSpotifyResolver(client *spotify.Client) *SpotifyResolver {
+	return &SpotifyResolver{Client: client}
+}
+
+func (r *SpotifyResolver) Resolve(ctx context.Context, artist, title string) (*MatchResult, error) {
+	tracks, err := r.Client.SearchTracks(ctx, fmt.Sprintf("track:%s artist:%s", title, artist), 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(tracks) == 0 {
+		return nil, nil
+	}
+
+	return &MatchResult{
+		Provider:   "spotify",
+		URL:        "https://open.spotify.com/track/" + tracks[0].ID,
+		Confidence: 1.0,
+	}, nil
+}
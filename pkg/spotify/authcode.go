@@ -0,0 +1,203 @@
+package spotify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"runtime"
+	"time"
+)
+
+const authorizeURL = "https://accounts.spotify.com/authorize"
+
+// AuthCodeFlow runs the OAuth Authorization Code flow with PKCE, the only
+// flow that can mint user-scoped tokens (playlists, saved tracks, playback).
+// It opens the system browser to Spotify's consent screen and listens on a
+// local callback server for the redirect.
+type AuthCodeFlow struct {
+	ClientID    string
+	RedirectURL string // e.g. http://127.0.0.1:8732/callback
+	Scopes      []string
+}
+
+// Run performs the full flow and returns the resulting UserToken. It blocks
+// until the user completes (or abandons) the consent screen or ctx is done.
+func (f *AuthCodeFlow) Run(ctx context.Context) (*UserToken, error) {
+	redirect, err := url.Parse(f.RedirectURL)
+	if err != nil {
+		return nil, fmt.Errorf("spotify: invalid redirect URL: %w", err)
+	}
+
+	verifier, err := newPKCEVerifier()
+	if err != nil {
+		return nil, err
+	}
+	state, err := newPKCEVerifier() // reuse the same random generator for the state nonce
+	if err != nil {
+		return nil, err
+	}
+
+	type callbackResult struct {
+		code string
+		err  error
+	}
+	resultCh := make(chan callbackResult, 1)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(redirect.Path, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if errStr := q.Get("error"); errStr != "" {
+			resultCh <- callbackResult{err: fmt.Errorf("spotify: authorization denied: %s", errStr)}
+			fmt.Fprint(w, "Authorization failed, you can close this window.")
+			return
+		}
+		if q.Get("state") != state {
+			resultCh <- callbackResult{err: fmt.Errorf("spotify: state mismatch in callback")}
+			fmt.Fprint(w, "Authorization failed, you can close this window.")
+			return
+		}
+		resultCh <- callbackResult{code: q.Get("code")}
+		fmt.Fprint(w, "Templay is connected to Spotify. You can close this window.")
+	})
+
+	srv := &http.Server{Addr: redirect.Host, Handler: mux}
+	srvErrCh := make(chan error, 1)
+	go func() { srvErrCh <- srv.ListenAndServe() }()
+	defer srv.Close()
+
+	authURL := f.buildAuthorizeURL(redirect.String(), state, verifier)
+	if err := openBrowser(authURL); err != nil {
+		return nil, fmt.Errorf("spotify: open browser: %w", err)
+	}
+
+	select {
+	case res := <-resultCh:
+		if res.err != nil {
+			return nil, res.err
+		}
+		return f.exchangeCode(ctx, res.code, redirect.String(), verifier)
+	case err := <-srvErrCh:
+		if err != nil && err != http.ErrServerClosed {
+			return nil, fmt.Errorf("spotify: callback server: %w", err)
+		}
+		return nil, fmt.Errorf("spotify: callback server stopped before receiving a callback")
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// AuthorizeURL builds the Spotify consent-screen URL for a caller-managed
+// callback (as opposed to Run, which hosts its own local callback server).
+// The caller is responsible for generating and remembering state and
+// verifier, then passing the same verifier to ExchangeCode once the
+// redirect comes back.
+func (f *AuthCodeFlow) AuthorizeURL(state, verifier string) string {
+	return f.buildAuthorizeURL(f.RedirectURL, state, verifier)
+}
+
+// ExchangeCode trades an authorization code from a caller-managed callback
+// for a UserToken, using the verifier passed to the matching AuthorizeURL
+// call.
+func (f *AuthCodeFlow) ExchangeCode(ctx context.Context, code, verifier string) (*UserToken, error) {
+	return f.exchangeCode(ctx, code, f.RedirectURL, verifier)
+}
+
+func (f *AuthCodeFlow) buildAuthorizeURL(redirectURL, state, verifier string) string {
+	q := url.Values{}
+	q.Set("client_id", f.ClientID)
+	q.Set("response_type", "code")
+	q.Set("redirect_uri", redirectURL)
+	q.Set("state", state)
+	q.Set("code_challenge_method", "S256")
+	q.Set("code_challenge", pkceChallengeS256(verifier))
+	if len(f.Scopes) > 0 {
+		scopes := f.Scopes[0]
+		for _, s := range f.Scopes[1:] {
+			scopes += " " + s
+		}
+		q.Set("scope", scopes)
+	}
+	return authorizeURL + "?" + q.Encode()
+}
+
+func (f *AuthCodeFlow) exchangeCode(ctx context.Context, code, redirectURL, verifier string) (*UserToken, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", redirectURL)
+	form.Set("client_id", f.ClientID)
+	form.Set("code_verifier", verifier)
+
+	return postTokenForm(ctx, http.DefaultClient, form)
+}
+
+// refreshUserToken exchanges a refresh token for a new access token.
+func refreshUserToken(ctx context.Context, h httpDoer, clientID, refreshToken string) (*UserToken, error) {
+	form := url.Values{}
+	form.Set("grant_type", "refresh_token")
+	form.Set("refresh_token", refreshToken)
+	form.Set("client_id", clientID)
+
+	tok, err := postTokenForm(ctx, h, form)
+	if err != nil {
+		return nil, err
+	}
+	if tok.RefreshToken == "" {
+		// Spotify does not always return a new refresh token; keep the old one.
+		tok.RefreshToken = refreshToken
+	}
+	return tok, nil
+}
+
+func postTokenForm(ctx context.Context, h httpDoer, form url.Values) (*UserToken, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, authURL, bytes.NewBufferString(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := h.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var buf bytes.Buffer
+		_, _ = buf.ReadFrom(resp.Body)
+		return nil, fmt.Errorf("spotify: token request status %s: %s", resp.Status, buf.String())
+	}
+
+	var payload struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		Scope        string `json:"scope"`
+		ExpiresIn    int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, err
+	}
+
+	return &UserToken{
+		AccessToken:  payload.AccessToken,
+		RefreshToken: payload.RefreshToken,
+		Scope:        payload.Scope,
+		Expiry:       time.Now().Add(time.Duration(payload.ExpiresIn) * time.Second),
+	}, nil
+}
+
+// openBrowser launches the system's default browser on the current OS.
+func openBrowser(rawURL string) error {
+	switch runtime.GOOS {
+	case "darwin":
+		return exec.Command("open", rawURL).Start()
+	case "windows":
+		return exec.Command("rundll32", "url.dll,FileProtocolHandler", rawURL).Start()
+	default:
+		return exec.Command("xdg-open", rawURL).Start()
+	}
+}
@@ -0,0 +1,485 @@
+// Package spotify provides a small client for the subset of the Spotify Web
+// API that Templay needs: searching tracks/artists and reading audio
+// features. It mirrors the shape of Navidrome's core/agents/spotify agent:
+// a single Client holds a cached bearer token and transparently re-authorizes
+// when it is close to expiry or the API responds with 401.
+package spotify
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	authURL = "https://accounts.spotify.com/api/token"
+	apiURL  = "https://api.spotify.com/v1"
+
+	// tokenRefreshSkew is how long before the real expiry we consider a
+	// cached token stale, so a request started just before expiry doesn't
+	// race the clock.
+	tokenRefreshSkew = 30 * time.Second
+
+	maxRetries = 3
+)
+
+// httpDoer is satisfied by *http.Client. Accepting it instead of a concrete
+// client lets tests stub transport behavior.
+type httpDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Client is a Spotify Web API client with in-memory token caching. By
+// default it authorizes with client credentials; call WithUserToken to
+// switch to a user-scoped token obtained via AuthCodeFlow, which is required
+// for playlist and playback endpoints.
+type Client struct {
+	clientID     string
+	clientSecret string
+	httpClient   httpDoer
+
+	mu          sync.Mutex
+	token       string
+	tokenExpiry time.Time
+	userToken   *UserToken
+	tokenStore  TokenStore
+}
+
+// NewClient builds a Client using client-credentials authorization. The
+// default *http.Client is used for transport; override it with WithHTTPClient
+// for tests.
+func NewClient(clientID, clientSecret string) *Client {
+	return &Client{
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		httpClient:   http.DefaultClient,
+	}
+}
+
+// WithHTTPClient overrides the transport used for requests (and token
+// fetches), returning the same Client for chaining.
+func (c *Client) WithHTTPClient(h httpDoer) *Client {
+	c.httpClient = h
+	return c
+}
+
+// WithUserToken switches the Client to user-scoped authorization, required
+// for endpoints like playlists, saved tracks, and playback. tok is typically
+// obtained via AuthCodeFlow.Run or loaded from a TokenStore.
+func (c *Client) WithUserToken(tok *UserToken) *Client {
+	c.mu.Lock()
+	c.userToken = tok
+	c.mu.Unlock()
+	return c
+}
+
+// WithTokenStore configures where a refreshed user token is persisted.
+// Required for StartUserTokenRefresh to save tokens it renews.
+func (c *Client) WithTokenStore(store TokenStore) *Client {
+	c.mu.Lock()
+	c.tokenStore = store
+	c.mu.Unlock()
+	return c
+}
+
+// StartUserTokenRefresh runs a background goroutine that renews the current
+// user token shortly before it expires, persisting it via the configured
+// TokenStore. It stops when ctx is done or once WithUserToken has not been
+// called. The returned context.CancelFunc stops the goroutine immediately;
+// callers that replace a Client's user token (e.g. re-authorizing a session)
+// must call it for the superseded Client to avoid leaking the goroutine.
+func (c *Client) StartUserTokenRefresh(ctx context.Context) context.CancelFunc {
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		for {
+			c.mu.Lock()
+			var tok *UserToken
+			if c.userToken != nil {
+				copied := *c.userToken
+				tok = &copied
+			}
+			c.mu.Unlock()
+			if tok == nil {
+				return
+			}
+
+			wait := time.Until(tok.Expiry.Add(-tokenRefreshSkew))
+			if wait < 0 {
+				wait = 0
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(wait):
+			}
+
+			if _, err := c.refreshStoredUserToken(ctx, tok.RefreshToken); err != nil {
+				return
+			}
+		}
+	}()
+	return cancel
+}
+
+// refreshStoredUserToken exchanges refreshToken for a new user token,
+// installs it, and persists it to the configured TokenStore, if any.
+func (c *Client) refreshStoredUserToken(ctx context.Context, refreshToken string) (string, error) {
+	tok, err := refreshUserToken(ctx, c.httpClient, c.clientID, refreshToken)
+	if err != nil {
+		return "", err
+	}
+
+	c.mu.Lock()
+	c.userToken = tok
+	store := c.tokenStore
+	c.mu.Unlock()
+
+	if store != nil {
+		if err := store.Save(tok); err != nil {
+			return "", fmt.Errorf("spotify: persist refreshed token: %w", err)
+		}
+	}
+	return tok.AccessToken, nil
+}
+
+// Artist is a minimal artist as returned by Spotify search.
+type Artist struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Track is a minimal track as returned by Spotify search.
+type Track struct {
+	ID      string   `json:"id"`
+	Name    string   `json:"name"`
+	Artists []Artist `json:"artists"`
+}
+
+// AudioFeatures holds the fields of Spotify's audio-features response that
+// Templay cares about.
+type AudioFeatures struct {
+	ID    string  `json:"id"`
+	Tempo float64 `json:"tempo"`
+}
+
+// accessToken returns a bearer token, fetching or refreshing one if needed.
+// If WithUserToken has installed a user-scoped token it takes priority over
+// client-credentials, since it's the only kind that can reach user-scoped
+// endpoints.
+func (c *Client) accessToken(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	userTok, userTokExpired := (*UserToken)(nil), false
+	if c.userToken != nil {
+		tok := *c.userToken
+		userTok, userTokExpired = &tok, c.userToken.expired()
+	}
+	c.mu.Unlock()
+
+	if userTok != nil {
+		if !userTokExpired {
+			return userTok.AccessToken, nil
+		}
+		return c.refreshStoredUserToken(ctx, userTok.RefreshToken)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token != "" && time.Now().Before(c.tokenExpiry.Add(-tokenRefreshSkew)) {
+		return c.token, nil
+	}
+
+	token, expiresIn, err := c.fetchToken(ctx)
+	if err != nil {
+		return "", err
+	}
+	c.token = token
+	c.tokenExpiry = time.Now().Add(time.Duration(expiresIn) * time.Second)
+	return c.token, nil
+}
+
+// invalidateToken forces the next accessToken call to re-authorize, used
+// after a request comes back 401 despite a non-expired cached token.
+func (c *Client) invalidateToken() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.token = ""
+	if c.userToken != nil {
+		c.userToken.Expiry = time.Time{}
+	}
+}
+
+func (c *Client) fetchToken(ctx context.Context) (string, int, error) {
+	basic := base64.StdEncoding.EncodeToString([]byte(c.clientID + ":" + c.clientSecret))
+
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, authURL, bytes.NewBufferString(form.Encode()))
+	if err != nil {
+		return "", 0, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Authorization", "Basic "+basic)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var buf bytes.Buffer
+		_, _ = buf.ReadFrom(resp.Body)
+		return "", 0, fmt.Errorf("spotify: token request status %s: %s", resp.Status, buf.String())
+	}
+
+	var payload struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+		TokenType   string `json:"token_type"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return "", 0, err
+	}
+	return payload.AccessToken, payload.ExpiresIn, nil
+}
+
+// doAuthorized sends req with a bearer token attached, retrying with backoff
+// on 429 (honoring Retry-After) and re-authorizing once on 401.
+func (c *Client) doAuthorized(ctx context.Context, req *http.Request) (*http.Response, error) {
+	token, err := c.accessToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reauthorized := false
+	for attempt := 0; ; attempt++ {
+		req.Header.Set("Authorization", "Bearer "+token)
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case resp.StatusCode == http.StatusTooManyRequests && attempt < maxRetries:
+			wait := retryAfter(resp.Header.Get("Retry-After"))
+			resp.Body.Close()
+			select {
+			case <-time.After(wait):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+			continue
+
+		case resp.StatusCode == http.StatusUnauthorized && !reauthorized:
+			resp.Body.Close()
+			c.invalidateToken()
+			token, err = c.accessToken(ctx)
+			if err != nil {
+				return nil, err
+			}
+			reauthorized = true
+			continue
+		}
+
+		return resp, nil
+	}
+}
+
+// retryAfter parses Spotify's Retry-After header (seconds), falling back to
+// a 1s backoff when absent or malformed.
+func retryAfter(header string) time.Duration {
+	if header == "" {
+		return time.Second
+	}
+	secs, err := strconv.Atoi(strings.TrimSpace(header))
+	if err != nil || secs < 0 {
+		return time.Second
+	}
+	return time.Duration(secs) * time.Second
+}
+
+// SearchTracks searches for tracks matching query, returning up to limit
+// results.
+func (c *Client) SearchTracks(ctx context.Context, query string, limit int) ([]Track, error) {
+	q := url.Values{}
+	q.Set("q", query)
+	q.Set("type", "track")
+	q.Set("limit", strconv.Itoa(limit))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL+"/search?"+q.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.doAuthorized(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		var buf bytes.Buffer
+		_, _ = buf.ReadFrom(resp.Body)
+		return nil, fmt.Errorf("spotify: search failed: %s: %s", resp.Status, buf.String())
+	}
+
+	var out struct {
+		Tracks struct {
+			Items []Track `json:"items"`
+		} `json:"tracks"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, err
+	}
+	return out.Tracks.Items, nil
+}
+
+// SearchArtists searches for artists matching name, returning up to limit
+// results.
+func (c *Client) SearchArtists(ctx context.Context, name string, limit int) ([]Artist, error) {
+	q := url.Values{}
+	q.Set("q", name)
+	q.Set("type", "artist")
+	q.Set("limit", strconv.Itoa(limit))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL+"/search?"+q.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.doAuthorized(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		var buf bytes.Buffer
+		_, _ = buf.ReadFrom(resp.Body)
+		return nil, fmt.Errorf("spotify: search failed: %s: %s", resp.Status, buf.String())
+	}
+
+	var out struct {
+		Artists struct {
+			Items []Artist `json:"items"`
+		} `json:"artists"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, err
+	}
+	return out.Artists.Items, nil
+}
+
+// RecommendationParams configures a call to Spotify's /recommendations
+// endpoint. At most 5 seed values (genres + artists + tracks combined) are
+// accepted by Spotify; callers are responsible for staying under that cap.
+type RecommendationParams struct {
+	SeedGenres  []string
+	SeedArtists []string
+	TargetTempo float64
+	MinTempo    float64
+	MaxTempo    float64
+	Limit       int
+}
+
+// Recommendations fetches tracks from Spotify's /recommendations endpoint
+// seeded by genres/artists and constrained to a tempo range.
+func (c *Client) Recommendations(ctx context.Context, params RecommendationParams) ([]Track, error) {
+	limit := params.Limit
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	q := url.Values{}
+	q.Set("limit", strconv.Itoa(limit))
+	if len(params.SeedGenres) > 0 {
+		q.Set("seed_genres", strings.Join(params.SeedGenres, ","))
+	}
+	if len(params.SeedArtists) > 0 {
+		q.Set("seed_artists", strings.Join(params.SeedArtists, ","))
+	}
+	if params.TargetTempo > 0 {
+		q.Set("target_tempo", strconv.FormatFloat(params.TargetTempo, 'f', 2, 64))
+	}
+	if params.MinTempo > 0 {
+		q.Set("min_tempo", strconv.FormatFloat(params.MinTempo, 'f', 2, 64))
+	}
+	if params.MaxTempo > 0 {
+		q.Set("max_tempo", strconv.FormatFloat(params.MaxTempo, 'f', 2, 64))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL+"/recommendations?"+q.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.doAuthorized(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		var buf bytes.Buffer
+		_, _ = buf.ReadFrom(resp.Body)
+		return nil, fmt.Errorf("spotify: recommendations failed: %s: %s", resp.Status, buf.String())
+	}
+
+	var out struct {
+		Tracks []Track `json:"tracks"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, err
+	}
+	return out.Tracks, nil
+}
+
+// GetAudioFeatures fetches audio features (including tempo) for up to 100
+// track IDs, Spotify's batch limit for this endpoint.
+func (c *Client) GetAudioFeatures(ctx context.Context, trackIDs []string) ([]AudioFeatures, error) {
+	if len(trackIDs) == 0 {
+		return nil, nil
+	}
+	if len(trackIDs) > 100 {
+		return nil, fmt.Errorf("spotify: GetAudioFeatures accepts at most 100 track IDs, got %d", len(trackIDs))
+	}
+
+	q := url.Values{}
+	q.Set("ids", strings.Join(trackIDs, ","))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL+"/audio-features?"+q.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.doAuthorized(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		var buf bytes.Buffer
+		_, _ = buf.ReadFrom(resp.Body)
+		return nil, fmt.Errorf("spotify: audio-features failed: %s: %s", resp.Status, buf.String())
+	}
+
+	var out struct {
+		AudioFeatures []AudioFeatures `json:"audio_features"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, err
+	}
+	return out.AudioFeatures, nil
+}
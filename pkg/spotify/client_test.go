@@ -0,0 +1,108 @@
+package spotify
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// stepDoer replays a fixed sequence of responses regardless of request
+// content, advancing one step per call and repeating the last response once
+// exhausted. It lets tests script a client-credentials fetch followed by a
+// search call without caring about exact URLs.
+type stepDoer struct {
+	responses []*http.Response
+	requests  []*http.Request
+	step      int
+}
+
+func (d *stepDoer) Do(req *http.Request) (*http.Response, error) {
+	d.requests = append(d.requests, req)
+	resp := d.responses[d.step]
+	if d.step < len(d.responses)-1 {
+		d.step++
+	}
+	return resp, nil
+}
+
+func jsonResponse(status int, body string, header http.Header) *http.Response {
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{StatusCode: status, Header: header, Body: io.NopCloser(strings.NewReader(body))}
+}
+
+const tokenBody = `{"access_token":"token-1","expires_in":3600}`
+const searchBody = `{"tracks":{"items":[{"id":"abc","name":"Song","artists":[{"id":"1","name":"Artist"}]}]}}`
+
+func TestSearchTracksRetriesOn429WithRetryAfter(t *testing.T) {
+	doer := &stepDoer{responses: []*http.Response{
+		jsonResponse(http.StatusOK, tokenBody, nil),
+		jsonResponse(http.StatusTooManyRequests, "", http.Header{"Retry-After": []string{"0"}}),
+		jsonResponse(http.StatusOK, searchBody, nil),
+	}}
+
+	client := NewClient("id", "secret").WithHTTPClient(doer)
+
+	tracks, err := client.SearchTracks(context.Background(), "test", 1)
+	if err != nil {
+		t.Fatalf("SearchTracks: %v", err)
+	}
+	if len(tracks) != 1 || tracks[0].Name != "Song" {
+		t.Fatalf("unexpected tracks: %+v", tracks)
+	}
+	if len(doer.requests) != 3 {
+		t.Fatalf("expected 3 requests (token, 429, retry), got %d", len(doer.requests))
+	}
+}
+
+func TestSearchTracksReauthorizesOn401(t *testing.T) {
+	doer := &stepDoer{responses: []*http.Response{
+		jsonResponse(http.StatusOK, tokenBody, nil),
+		jsonResponse(http.StatusUnauthorized, "", nil),
+		jsonResponse(http.StatusOK, tokenBody, nil),
+		jsonResponse(http.StatusOK, searchBody, nil),
+	}}
+
+	client := NewClient("id", "secret").WithHTTPClient(doer)
+
+	tracks, err := client.SearchTracks(context.Background(), "test", 1)
+	if err != nil {
+		t.Fatalf("SearchTracks: %v", err)
+	}
+	if len(tracks) != 1 {
+		t.Fatalf("unexpected tracks: %+v", tracks)
+	}
+	if len(doer.requests) != 4 {
+		t.Fatalf("expected 4 requests (token, 401, re-auth, retry), got %d", len(doer.requests))
+	}
+}
+
+func TestAccessTokenCachesUntilExpiry(t *testing.T) {
+	doer := &stepDoer{responses: []*http.Response{
+		jsonResponse(http.StatusOK, tokenBody, nil),
+		jsonResponse(http.StatusOK, searchBody, nil),
+		jsonResponse(http.StatusOK, searchBody, nil),
+	}}
+
+	client := NewClient("id", "secret").WithHTTPClient(doer)
+
+	if _, err := client.SearchTracks(context.Background(), "a", 1); err != nil {
+		t.Fatalf("first SearchTracks: %v", err)
+	}
+	if _, err := client.SearchTracks(context.Background(), "b", 1); err != nil {
+		t.Fatalf("second SearchTracks: %v", err)
+	}
+
+	tokenRequests := 0
+	for _, req := range doer.requests {
+		if req.URL.String() == authURL {
+			tokenRequests++
+		}
+	}
+	if tokenRequests != 1 {
+		t.Fatalf("expected exactly 1 token request across two searches, got %d", tokenRequests)
+	}
+}
@@ -0,0 +1,39 @@
+package spotify
+
+import "testing"
+
+func TestNewPKCEVerifierIsURLSafeAndUnique(t *testing.T) {
+	a, err := newPKCEVerifier()
+	if err != nil {
+		t.Fatalf("newPKCEVerifier: %v", err)
+	}
+	b, err := newPKCEVerifier()
+	if err != nil {
+		t.Fatalf("newPKCEVerifier: %v", err)
+	}
+
+	if a == b {
+		t.Fatal("two calls to newPKCEVerifier produced the same verifier")
+	}
+	if len(a) < 43 || len(a) > 128 {
+		t.Fatalf("verifier length %d outside Spotify's 43-128 requirement", len(a))
+	}
+	for _, r := range a {
+		if !((r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '-' || r == '_') {
+			t.Fatalf("verifier contains non-base64url character %q", r)
+		}
+	}
+}
+
+func TestPKCEChallengeS256IsDeterministic(t *testing.T) {
+	const verifier = "test-verifier-value"
+
+	a := pkceChallengeS256(verifier)
+	b := pkceChallengeS256(verifier)
+	if a != b {
+		t.Fatalf("pkceChallengeS256 not deterministic: %q != %q", a, b)
+	}
+	if a == verifier {
+		t.Fatal("challenge equals verifier, expected a hashed value")
+	}
+}
@@ -0,0 +1,115 @@
+package spotify
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// UserToken is a user-scoped OAuth token obtained via the Authorization Code
+// flow, along with enough bookkeeping to refresh it.
+type UserToken struct {
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token"`
+	Scope        string    `json:"scope"`
+	Expiry       time.Time `json:"expiry"`
+}
+
+// expired reports whether the token is within tokenRefreshSkew of expiring.
+func (t *UserToken) expired() bool {
+	return t == nil || time.Now().After(t.Expiry.Add(-tokenRefreshSkew))
+}
+
+// TokenStore persists a UserToken between runs. FileTokenStore is the
+// built-in implementation; a keyring-backed store can satisfy the same
+// interface for callers that prefer not to touch disk.
+type TokenStore interface {
+	Load() (*UserToken, error)
+	Save(tok *UserToken) error
+}
+
+// FileTokenStore persists a UserToken to a single file, encrypted at rest
+// with AES-GCM under Key. Load returns (nil, nil) when the file does not
+// exist yet, so callers can treat "no token" as "needs login".
+type FileTokenStore struct {
+	Path string
+	Key  [32]byte
+}
+
+// NewFileTokenStore builds a FileTokenStore. Key must be 32 bytes (AES-256);
+// callers typically derive it from a passphrase or OS-level secret.
+func NewFileTokenStore(path string, key [32]byte) *FileTokenStore {
+	return &FileTokenStore{Path: path, Key: key}
+}
+
+func (s *FileTokenStore) Load() (*UserToken, error) {
+	ciphertext, err := os.ReadFile(s.Path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := s.decrypt(ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("spotify: decrypt token store: %w", err)
+	}
+
+	var tok UserToken
+	if err := json.Unmarshal(plaintext, &tok); err != nil {
+		return nil, err
+	}
+	return &tok, nil
+}
+
+func (s *FileTokenStore) Save(tok *UserToken) error {
+	plaintext, err := json.Marshal(tok)
+	if err != nil {
+		return err
+	}
+
+	ciphertext, err := s.encrypt(plaintext)
+	if err != nil {
+		return fmt.Errorf("spotify: encrypt token store: %w", err)
+	}
+	return os.WriteFile(s.Path, ciphertext, 0o600)
+}
+
+func (s *FileTokenStore) gcm() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(s.Key[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func (s *FileTokenStore) encrypt(plaintext []byte) ([]byte, error) {
+	gcm, err := s.gcm()
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func (s *FileTokenStore) decrypt(ciphertext []byte) ([]byte, error) {
+	gcm, err := s.gcm()
+	if err != nil {
+		return nil, err
+	}
+	nonceSize := gcm.NonceSize()
+	if len(ciphertext) < nonceSize {
+		return nil, fmt.Errorf("token store file is too short")
+	}
+	nonce, sealed := ciphertext[:nonceSize], ciphertext[nonceSize:]
+	return gcm.Open(nil, nonce, sealed, nil)
+}
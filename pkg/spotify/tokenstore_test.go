@@ -0,0 +1,61 @@
+package spotify
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileTokenStoreRoundTrip(t *testing.T) {
+	var key [32]byte
+	copy(key[:], []byte("0123456789abcdef0123456789abcdef"))
+
+	store := NewFileTokenStore(filepath.Join(t.TempDir(), "token"), key)
+
+	want := &UserToken{
+		AccessToken:  "access-1",
+		RefreshToken: "refresh-1",
+		Scope:        "playlist-modify-public",
+		Expiry:       time.Now().Add(time.Hour).Truncate(time.Second),
+	}
+	if err := store.Save(want); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	got, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got.AccessToken != want.AccessToken || got.RefreshToken != want.RefreshToken ||
+		got.Scope != want.Scope || !got.Expiry.Equal(want.Expiry) {
+		t.Fatalf("Load() = %+v, want %+v", got, want)
+	}
+}
+
+func TestFileTokenStoreLoadMissingFileReturnsNil(t *testing.T) {
+	var key [32]byte
+	store := NewFileTokenStore(filepath.Join(t.TempDir(), "missing"), key)
+
+	got, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got != nil {
+		t.Fatalf("Load() = %+v, want nil", got)
+	}
+}
+
+func TestFileTokenStoreWrongKeyFailsToDecrypt(t *testing.T) {
+	var key1, key2 [32]byte
+	copy(key1[:], []byte("0123456789abcdef0123456789abcdef"))
+	copy(key2[:], []byte("fedcba9876543210fedcba9876543210"))
+
+	path := filepath.Join(t.TempDir(), "token")
+	if err := NewFileTokenStore(path, key1).Save(&UserToken{AccessToken: "a"}); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	if _, err := NewFileTokenStore(path, key2).Load(); err == nil {
+		t.Fatal("Load with wrong key succeeded, want error")
+	}
+}